@@ -0,0 +1,118 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Decoder reads a GeoJSON FeatureCollection from a stream, yielding one
+// Feature at a time instead of buffering the whole document in memory.
+// This makes it practical to process multi-GB extracts (e.g. OSM-style
+// polygon dumps used for "limit-to" region clipping) that would be too
+// large to json.Unmarshal whole.
+//
+// Typical use:
+//
+//	dec := geojson.NewDecoder(r)
+//	if _, err := dec.DecodeCollectionHeader(); err != nil {
+//		// handle err
+//	}
+//	for {
+//		f, err := dec.Decode()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			// handle err
+//		}
+//		// use f
+//	}
+type Decoder struct {
+	dec     *json.Decoder
+	inArray bool
+}
+
+// NewDecoder returns a Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// DecodeCollectionHeader reads up to and including the opening "[" of
+// the FeatureCollection's "features" array, returning a
+// FeatureCollection populated with Type, Bbox and Crs but with
+// Features left nil. Decode must be called afterwards to read each
+// feature in turn.
+func (d *Decoder) DecodeCollectionHeader() (*FeatureCollection, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("geojson: expected a JSON object")
+	}
+
+	fc := &FeatureCollection{}
+	for d.dec.More() {
+		keyTok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := keyTok.(string)
+		switch key {
+		case "type":
+			if err := d.dec.Decode(&fc.Type); err != nil {
+				return nil, err
+			}
+			if fc.Type != "FeatureCollection" {
+				return nil, fmt.Errorf("geojson: expected FeatureCollection, got %s", fc.Type)
+			}
+		case "bbox":
+			if err := d.dec.Decode(&fc.Bbox); err != nil {
+				return nil, err
+			}
+		case "crs":
+			if err := d.dec.Decode(&fc.Crs); err != nil {
+				return nil, err
+			}
+		case "features":
+			arrTok, err := d.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if delim, ok := arrTok.(json.Delim); !ok || delim != '[' {
+				return nil, errors.New(`geojson: expected "features" to be an array`)
+			}
+			d.inArray = true
+			return fc, nil
+		default:
+			var skip interface{}
+			if err := d.dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, errors.New(`geojson: FeatureCollection has no "features" member`)
+}
+
+// Decode reads the next Feature from the "features" array. It returns
+// io.EOF once the array is exhausted. DecodeCollectionHeader must be
+// called first.
+func (d *Decoder) Decode() (*Feature, error) {
+	if !d.inArray {
+		return nil, errors.New("geojson: DecodeCollectionHeader must be called before Decode")
+	}
+	if !d.dec.More() {
+		d.inArray = false
+		if _, err := d.dec.Token(); err != nil { // consume closing "]"
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	f := &Feature{}
+	if err := d.dec.Decode(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
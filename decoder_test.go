@@ -0,0 +1,62 @@
+package geojson
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	fc := NewFeatureCollection([]*Feature{
+		NewFeature(NewPoint(Coordinate{X: 1, Y: 2}), map[string]interface{}{"name": "a"}, "a"),
+		NewFeature(NewPolygon(MultiLine{{
+			{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0},
+		}}), nil, "b"),
+	})
+	fc.Bbox = BoundingBox{0, 0, 1, 2}
+	fc.Crs = NewNamedCRS("urn:ogc:def:crs:OGC:1.3:CRS84")
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.EncodeHeader(fc); err != nil {
+		t.Fatalf("EncodeHeader: %v", err)
+	}
+	for _, f := range fc.Features {
+		if err := enc.Encode(f); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	dec := NewDecoder(&buf)
+	header, err := dec.DecodeCollectionHeader()
+	if err != nil {
+		t.Fatalf("DecodeCollectionHeader: %v", err)
+	}
+	if header.Type != "FeatureCollection" {
+		t.Fatalf("Type = %q, want FeatureCollection", header.Type)
+	}
+	if len(header.Bbox) != 4 || header.Bbox[3] != 2 {
+		t.Fatalf("Bbox = %+v, want round-tripped from the original", header.Bbox)
+	}
+
+	var got []*Feature
+	for {
+		f, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		got = append(got, f)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if _, err := got[1].GetGeometry(); err != nil {
+		t.Fatalf("GetGeometry on decoded feature: %v", err)
+	}
+}
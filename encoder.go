@@ -0,0 +1,89 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Encoder writes a GeoJSON FeatureCollection to a stream one Feature at
+// a time, the symmetric counterpart to Decoder: pipelines can filter or
+// transform features without ever materializing the whole collection.
+type Encoder struct {
+	w       io.Writer
+	enc     *json.Encoder
+	started bool
+	count   int
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, enc: json.NewEncoder(w)}
+}
+
+// EncodeHeader writes the opening of a FeatureCollection, copying Bbox
+// and Crs from fc if it is non-nil. It is called automatically by the
+// first Encode or Close if not called explicitly, in which case no
+// Bbox or Crs is written.
+func (e *Encoder) EncodeHeader(fc *FeatureCollection) error {
+	if e.started {
+		return errors.New("geojson: EncodeHeader already called")
+	}
+	if _, err := io.WriteString(e.w, `{"type":"FeatureCollection",`); err != nil {
+		return err
+	}
+	if fc != nil && fc.Bbox != nil {
+		b, err := json.Marshal(fc.Bbox)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, `"bbox":`+string(b)+`,`); err != nil {
+			return err
+		}
+	}
+	if fc != nil && fc.Crs != nil {
+		b, err := json.Marshal(fc.Crs)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, `"crs":`+string(b)+`,`); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(e.w, `"features":[`); err != nil {
+		return err
+	}
+	e.started = true
+	return nil
+}
+
+// Encode writes f as the next feature in the array.
+func (e *Encoder) Encode(f *Feature) error {
+	if !e.started {
+		if err := e.EncodeHeader(nil); err != nil {
+			return err
+		}
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	if err := e.enc.Encode(f); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Close writes the closing "]}" of the FeatureCollection. It must be
+// called once all features have been encoded.
+func (e *Encoder) Close() error {
+	if !e.started {
+		if err := e.EncodeHeader(nil); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.w, "]}")
+	return err
+}
@@ -25,9 +25,12 @@ type Feature struct {
 	Crs        *CRS                   `json:"crs,omitempty"`
 }
 
+// GetGeometry returns t's geometry, accepting it either already parsed
+// (as set by NewFeature) or still the map[string]interface{} left by
+// decoding JSON, which it parses on demand. The parsed result is not
+// stored back in t.Geometry.
 func (t *Feature) GetGeometry() (Geometry, error) {
-	gi := t.Geometry
-	return parseGeometry(gi)
+	return parseGeometry(t.Geometry)
 }
 
 // Factory constructor method
@@ -46,6 +49,12 @@ type FeatureCollection struct {
 	Features []*Feature  `json:"features"`
 	Bbox     BoundingBox `json:"bbox,omitempty"`
 	Crs      *CRS        `json:"crs,omitempty"`
+
+	// index caches the result of BuildIndex. It is unexported so it
+	// never reaches the JSON encoding, and AddFeatures drops it. An
+	// *Index that BuildIndex already returned keeps indexing the
+	// features it was built from.
+	index *Index
 }
 
 func (t *FeatureCollection) AddFeatures(f ...*Feature) {
@@ -53,6 +62,19 @@ func (t *FeatureCollection) AddFeatures(f ...*Feature) {
 		t.Features = make([]*Feature, 0, 100)
 	}
 	t.Features = append(t.Features, f...)
+	t.index = nil
+}
+
+// Filter returns a new FeatureCollection containing only the features
+// for which pred returns true.
+func (t *FeatureCollection) Filter(pred func(*Feature) bool) *FeatureCollection {
+	kept := make([]*Feature, 0, len(t.Features))
+	for _, f := range t.Features {
+		if pred(f) {
+			kept = append(kept, f)
+		}
+	}
+	return NewFeatureCollection(kept)
 }
 
 // factory funcion
@@ -106,12 +128,19 @@ func parseCoordinate(c interface{}) (coord Coordinate, err error) {
 		}
 	}()
 	coordinate, ok := c.([]interface{})
-	if !ok || len(coordinate) != 2 {
+	if !ok || len(coordinate) < 2 || len(coordinate) > 4 {
 		return Coordinate{}, fmt.Errorf("Error unmarshal %v to coordinates", c)
 	}
 	x := Coord(coordinate[0])
 	y := Coord(coordinate[1])
-	return Coordinate{x, y}, nil
+	switch len(coordinate) {
+	case 3:
+		return Coordinate{X: x, Y: y, Z: Coord(coordinate[2]), HasZ: true}, nil
+	case 4:
+		return Coordinate{X: x, Y: y, Z: Coord(coordinate[2]), HasZ: true, M: Coord(coordinate[3]), HasM: true}, nil
+	default:
+		return Coordinate{X: x, Y: y}, nil
+	}
 }
 
 func parseCoordinates(obj interface{}) (Coordinates, error) {
@@ -201,8 +230,16 @@ func parseMultiPolygon(obj interface{}) (*MultiPolygon, error) {
 	return NewMultiPolygon(ml), nil
 }
 
+// parseGeometry returns gi as a Geometry, accepting it either already
+// parsed or as the map[string]interface{} json.Unmarshal leaves it in.
 func parseGeometry(gi interface{}) (Geometry, error) {
-	g := gi.(map[string]interface{})
+	if g, ok := gi.(Geometry); ok {
+		return g, nil
+	}
+	g, ok := gi.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ParseError: cannot parse geometry from %T", gi)
+	}
 	coord := g["coordinates"]
 	switch typ := g["type"]; typ {
 	case "Point":
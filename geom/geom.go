@@ -0,0 +1,218 @@
+/*
+Package geom implements geometry operations (containment, intersection,
+clipping) over the geometry types in package geojson. It is kept
+separate from geojson itself so that package stays a pure
+(de)serialization layer.
+*/
+package geom
+
+import (
+	"errors"
+	"math"
+
+	"github.com/sputnik-maps/geojson"
+)
+
+// PointInPolygon reports whether c lies inside p, using ray casting
+// against the outer ring and treating any further rings as holes: a
+// point inside a hole is not considered inside the polygon.
+func PointInPolygon(c geojson.Coordinate, p *geojson.Polygon) bool {
+	rings := p.Coordinates
+	if len(rings) == 0 || !rayCast(c, rings[0]) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if rayCast(c, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+func rayCast(c geojson.Coordinate, ring geojson.Coordinates) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pi, pj := ring[i], ring[j]
+		if (pi.Y > c.Y) != (pj.Y > c.Y) &&
+			c.X < (pj.X-pi.X)*(c.Y-pi.Y)/(pj.Y-pi.Y)+pi.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// BBoxIntersects reports whether two bounding boxes overlap.
+func BBoxIntersects(a, b geojson.BoundingBox) bool {
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return a[0] <= b[2] && b[0] <= a[2] && a[1] <= b[3] && b[1] <= a[3]
+}
+
+// Clip intersects subject's outer ring against clipper's outer ring
+// using Sutherland-Hodgman, returning the result as a MultiPolygon.
+// Holes on either polygon are ignored. clipper must be convex - Clip
+// returns an error rather than a silently wrong result for a concave
+// one; decompose it into convex pieces (or its convex hull, if an
+// approximation is acceptable) first. isLeft's "inside" test assumes
+// clip winds counter-clockwise, so Clip reorders it first if it
+// doesn't; a clockwise clipper ring, common in real-world GeoJSON, used
+// to make Clip discard the whole subject silently.
+func Clip(subject, clipper *geojson.Polygon) (*geojson.MultiPolygon, error) {
+	if len(subject.Coordinates) == 0 || len(clipper.Coordinates) == 0 {
+		return nil, errors.New("geom: polygon has no rings")
+	}
+	clip := clipper.Coordinates[0]
+	if !isConvex(clip) {
+		return nil, errors.New("geom: clipper must be convex")
+	}
+	if signedArea(clip) < 0 {
+		clip = reverseRing(clip)
+	}
+	output := subject.Coordinates[0]
+	n := len(clip)
+	for i := 0; i < n && len(output) > 0; i++ {
+		output = clipEdge(output, clip[i], clip[(i+1)%n])
+	}
+	if len(output) == 0 {
+		return geojson.NewMultiPolygon(nil), nil
+	}
+	return geojson.NewMultiPolygon([]geojson.MultiLine{{output}}), nil
+}
+
+// signedArea returns twice the shoelace-formula area of ring (positive
+// for counter-clockwise winding, negative for clockwise); the factor of
+// two doesn't matter since only its sign is used.
+func signedArea(ring geojson.Coordinates) float64 {
+	var area float64
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i].X*ring[i+1].Y - ring[i+1].X*ring[i].Y
+	}
+	return area
+}
+
+func reverseRing(ring geojson.Coordinates) geojson.Coordinates {
+	out := make(geojson.Coordinates, len(ring))
+	for i, c := range ring {
+		out[len(ring)-1-i] = c
+	}
+	return out
+}
+
+// isConvex reports whether ring turns the same way (all left or all
+// right) at every vertex, treating a straight run as agreeing with
+// either. A ring with fewer than 3 distinct points isn't a polygon.
+func isConvex(ring geojson.Coordinates) bool {
+	pts := ring
+	if len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	n := len(pts)
+	if n < 3 {
+		return false
+	}
+	sign := 0
+	for i := 0; i < n; i++ {
+		a, b, c := pts[i], pts[(i+1)%n], pts[(i+2)%n]
+		cross := (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+		if cross == 0 {
+			continue
+		}
+		s := 1
+		if cross < 0 {
+			s = -1
+		}
+		if sign == 0 {
+			sign = s
+		} else if sign != s {
+			return false
+		}
+	}
+	return true
+}
+
+// clipEdge keeps the portion of poly on the left side of the directed
+// edge a->b, inserting an intersection point wherever the boundary is
+// crossed.
+func clipEdge(poly geojson.Coordinates, a, b geojson.Coordinate) geojson.Coordinates {
+	var out geojson.Coordinates
+	n := len(poly)
+	for i := 0; i < n; i++ {
+		cur := poly[i]
+		prev := poly[(i-1+n)%n]
+		curIn := isLeft(a, b, cur)
+		prevIn := isLeft(a, b, prev)
+		if curIn {
+			if !prevIn {
+				out = append(out, segmentIntersect(prev, cur, a, b))
+			}
+			out = append(out, cur)
+		} else if prevIn {
+			out = append(out, segmentIntersect(prev, cur, a, b))
+		}
+	}
+	return out
+}
+
+func isLeft(a, b, p geojson.Coordinate) bool {
+	return (b.X-a.X)*(p.Y-a.Y)-(b.Y-a.Y)*(p.X-a.X) >= 0
+}
+
+func segmentIntersect(p1, p2, a, b geojson.Coordinate) geojson.Coordinate {
+	denom := (p1.X-p2.X)*(a.Y-b.Y) - (p1.Y-p2.Y)*(a.X-b.X)
+	if math.Abs(denom) < 1e-12 {
+		// p1-p2 is parallel to (or collinear with) the clip edge, so
+		// there's no well-defined crossing point; p1 is already on the
+		// boundary side of the edge in every case clipEdge calls this
+		// for, so use it instead of dividing by ~0 into NaN/Inf.
+		return p1
+	}
+	t := ((p1.X-a.X)*(a.Y-b.Y) - (p1.Y-a.Y)*(a.X-b.X)) / denom
+	return geojson.Coordinate{X: p1.X + t*(p2.X-p1.X), Y: p1.Y + t*(p2.Y-p1.Y)}
+}
+
+// LimitTo clips every Polygon or MultiPolygon feature in fc against
+// limiter, dropping features whose clipped geometry becomes empty.
+// Features with other geometry types are passed through unchanged.
+// This mirrors the imposm3 "limit-to" workflow.
+func LimitTo(fc *geojson.FeatureCollection, limiter *geojson.Polygon) (*geojson.FeatureCollection, error) {
+	kept := make([]*geojson.Feature, 0, len(fc.Features))
+	for _, f := range fc.Features {
+		g, err := f.GetGeometry()
+		if err != nil {
+			return nil, err
+		}
+		switch t := g.(type) {
+		case *geojson.Polygon:
+			clipped, err := Clip(t, limiter)
+			if err != nil {
+				return nil, err
+			}
+			if len(clipped.Coordinates) == 0 {
+				continue
+			}
+			nf := *f
+			nf.Geometry = clipped
+			kept = append(kept, &nf)
+		case *geojson.MultiPolygon:
+			var parts []geojson.MultiLine
+			for _, ring := range t.Coordinates {
+				clipped, err := Clip(&geojson.Polygon{Type: "Polygon", Coordinates: ring}, limiter)
+				if err != nil {
+					return nil, err
+				}
+				parts = append(parts, clipped.Coordinates...)
+			}
+			if len(parts) == 0 {
+				continue
+			}
+			nf := *f
+			nf.Geometry = geojson.NewMultiPolygon(parts)
+			kept = append(kept, &nf)
+		default:
+			kept = append(kept, f)
+		}
+	}
+	return geojson.NewFeatureCollection(kept), nil
+}
@@ -0,0 +1,87 @@
+package geom
+
+import (
+	"testing"
+
+	"github.com/sputnik-maps/geojson"
+)
+
+func square(minX, minY, maxX, maxY float64) *geojson.Polygon {
+	ring := geojson.Coordinates{
+		{X: minX, Y: minY}, {X: maxX, Y: minY}, {X: maxX, Y: maxY}, {X: minX, Y: maxY}, {X: minX, Y: minY},
+	}
+	return geojson.NewPolygon(geojson.MultiLine{ring})
+}
+
+func reversedSquare(minX, minY, maxX, maxY float64) *geojson.Polygon {
+	ring := geojson.Coordinates{
+		{X: minX, Y: minY}, {X: minX, Y: maxY}, {X: maxX, Y: maxY}, {X: maxX, Y: minY}, {X: minX, Y: minY},
+	}
+	return geojson.NewPolygon(geojson.MultiLine{ring})
+}
+
+func TestClipOverlapping(t *testing.T) {
+	subject := square(0, 0, 10, 10)
+	clipper := square(5, 5, 15, 15)
+
+	mp, err := Clip(subject, clipper)
+	if err != nil {
+		t.Fatalf("Clip: %v", err)
+	}
+	if len(mp.Coordinates) != 1 || len(mp.Coordinates[0]) != 1 {
+		t.Fatalf("Coordinates = %+v, want one ring", mp.Coordinates)
+	}
+	for _, c := range mp.Coordinates[0][0] {
+		if c.X < 5 || c.X > 10 || c.Y < 5 || c.Y > 10 {
+			t.Fatalf("vertex %+v outside the expected overlap", c)
+		}
+	}
+}
+
+// TestClipClockwiseClipper makes sure a clockwise-wound clipper ring -
+// common in real-world GeoJSON - clips the same as its counter-clockwise
+// twin, instead of discarding the whole subject.
+func TestClipClockwiseClipper(t *testing.T) {
+	subject := square(0, 0, 10, 10)
+
+	ccw, err := Clip(subject, square(5, 5, 15, 15))
+	if err != nil {
+		t.Fatalf("Clip(ccw): %v", err)
+	}
+	cw, err := Clip(subject, reversedSquare(5, 5, 15, 15))
+	if err != nil {
+		t.Fatalf("Clip(cw): %v", err)
+	}
+	if len(cw.Coordinates) == 0 || len(cw.Coordinates[0]) == 0 {
+		t.Fatalf("clockwise clipper produced an empty result: %+v", cw.Coordinates)
+	}
+	if len(cw.Coordinates[0][0]) != len(ccw.Coordinates[0][0]) {
+		t.Fatalf("clockwise clip = %d vertices, counter-clockwise clip = %d vertices",
+			len(cw.Coordinates[0][0]), len(ccw.Coordinates[0][0]))
+	}
+}
+
+func TestClipConcaveClipperRejected(t *testing.T) {
+	subject := square(0, 0, 10, 10)
+	concave := geojson.NewPolygon(geojson.MultiLine{{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0},
+	}})
+
+	if _, err := Clip(subject, concave); err == nil {
+		t.Fatal("Clip with a concave clipper: want error, got nil")
+	}
+}
+
+func TestLimitToConcreteGeometry(t *testing.T) {
+	fc := geojson.NewFeatureCollection([]*geojson.Feature{
+		geojson.NewFeature(square(0, 0, 10, 10), nil, nil),
+	})
+
+	out, err := LimitTo(fc, square(5, 5, 15, 15))
+	if err != nil {
+		t.Fatalf("LimitTo: %v", err)
+	}
+	if len(out.Features) != 1 {
+		t.Fatalf("len(Features) = %d, want 1", len(out.Features))
+	}
+}
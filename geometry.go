@@ -0,0 +1,193 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Coord converts a decoded JSON coordinate value to float64, panicking
+// on anything else so callers can recover and turn it into a parse
+// error.
+func Coord(v interface{}) float64 {
+	f, ok := v.(float64)
+	if !ok {
+		panic(fmt.Sprintf("geojson: %v is not a coordinate value", v))
+	}
+	return f
+}
+
+// Coordinate is a single position. X and Y are always present. Z holds
+// the optional altitude carried as a third array element (RFC7946
+// permits this), and M holds a measure value carried as a fourth
+// element, mirroring how ecosystems like tegola distinguish Point2 from
+// Point3. HasZ and HasM record which of those were present so that
+// Marshal round-trips the coordinate's original array length.
+type Coordinate struct {
+	X, Y, Z, M float64
+	HasZ, HasM bool
+}
+
+// Dimension reports 2, 3 or 4 depending on whether Z and/or M are set.
+func (c Coordinate) Dimension() int {
+	switch {
+	case c.HasZ && c.HasM:
+		return 4
+	case c.HasZ || c.HasM:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// MarshalJSON emits [x,y], [x,y,z] or [x,y,z,m] depending on which of
+// HasZ/HasM are set. M is always written as the fourth element, so a
+// coordinate with HasM but not HasZ is written with Z in third place.
+func (c Coordinate) MarshalJSON() ([]byte, error) {
+	vals := []float64{c.X, c.Y}
+	if c.HasZ || c.HasM {
+		vals = append(vals, c.Z)
+	}
+	if c.HasM {
+		vals = append(vals, c.M)
+	}
+	return json.Marshal(vals)
+}
+
+// Coordinates is a list of positions, e.g. the points of a LineString
+// or the vertices of a single Polygon ring.
+type Coordinates []Coordinate
+
+func (cc Coordinates) dimension() int {
+	dim := 2
+	for _, c := range cc {
+		if d := c.Dimension(); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
+
+// MultiLine is a list of Coordinates, used both for MultiLineString (a
+// list of lines) and for Polygon (a list of rings, outer ring first).
+type MultiLine []Coordinates
+
+func (ml MultiLine) dimension() int {
+	dim := 2
+	for _, cc := range ml {
+		if d := cc.dimension(); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
+
+// Geometry is implemented by every concrete geometry type in this
+// package.
+type Geometry interface {
+	// Dimension reports 2, 3 or 4 depending on whether the geometry's
+	// coordinates carry a Z and/or M element.
+	Dimension() int
+}
+
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates Coordinate `json:"coordinates"`
+}
+
+func NewPoint(c Coordinate) *Point {
+	return &Point{Type: "Point", Coordinates: c}
+}
+
+func (p *Point) Dimension() int {
+	return p.Coordinates.Dimension()
+}
+
+type LineString struct {
+	Type        string      `json:"type"`
+	Coordinates Coordinates `json:"coordinates"`
+}
+
+func NewLineString(cc Coordinates) *LineString {
+	return &LineString{Type: "LineString", Coordinates: cc}
+}
+
+func (l *LineString) Dimension() int {
+	return l.Coordinates.dimension()
+}
+
+type MultiPoint struct {
+	Type        string      `json:"type"`
+	Coordinates Coordinates `json:"coordinates"`
+}
+
+func NewMultiPoint(cc Coordinates) *MultiPoint {
+	return &MultiPoint{Type: "MultiPoint", Coordinates: cc}
+}
+
+func (m *MultiPoint) Dimension() int {
+	return m.Coordinates.dimension()
+}
+
+type MultiLineString struct {
+	Type        string    `json:"type"`
+	Coordinates MultiLine `json:"coordinates"`
+}
+
+func NewMultiLineString(ml MultiLine) *MultiLineString {
+	return &MultiLineString{Type: "MultiLineString", Coordinates: ml}
+}
+
+func (m *MultiLineString) Dimension() int {
+	return m.Coordinates.dimension()
+}
+
+type Polygon struct {
+	Type        string    `json:"type"`
+	Coordinates MultiLine `json:"coordinates"`
+}
+
+func NewPolygon(pl MultiLine) *Polygon {
+	return &Polygon{Type: "Polygon", Coordinates: pl}
+}
+
+func (p *Polygon) Dimension() int {
+	return p.Coordinates.dimension()
+}
+
+type MultiPolygon struct {
+	Type        string      `json:"type"`
+	Coordinates []MultiLine `json:"coordinates"`
+}
+
+func NewMultiPolygon(ml []MultiLine) *MultiPolygon {
+	return &MultiPolygon{Type: "MultiPolygon", Coordinates: ml}
+}
+
+func (m *MultiPolygon) Dimension() int {
+	dim := 2
+	for _, pl := range m.Coordinates {
+		if d := pl.dimension(); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
+
+type GeometryCollection struct {
+	Type       string     `json:"type"`
+	Geometries []Geometry `json:"geometries"`
+}
+
+func NewGeometryCollection() *GeometryCollection {
+	return &GeometryCollection{Type: "GeometryCollection", Geometries: make([]Geometry, 0)}
+}
+
+func (g *GeometryCollection) Dimension() int {
+	dim := 2
+	for _, geom := range g.Geometries {
+		if d := geom.Dimension(); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
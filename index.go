@@ -0,0 +1,404 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// indexFanout is the target number of entries per R-tree node, both at
+// the leaf level (features per leaf) and at every level above it
+// (children per internal node).
+const indexFanout = 16
+
+// Index is an in-memory spatial index over a FeatureCollection's
+// features, built by BuildIndex using STR (sort-tile-recursive) bulk
+// loading. It is not attached to the JSON representation: it lives only
+// in memory and is discarded by AddFeatures, since it indexes a
+// snapshot of Features that mutation would make stale.
+type Index struct {
+	root *indexNode
+
+	// Refine, when true, makes SearchBBox and SearchPoint follow up a
+	// bbox match with a closer geometry test instead of returning
+	// every feature whose bounding box merely overlaps the query.
+	Refine bool
+}
+
+// indexNode is either a leaf, holding a slice of features directly, or
+// an internal node, holding child nodes. bbox is the union of whatever
+// the node holds.
+type indexNode struct {
+	bbox     BoundingBox
+	children []*indexNode
+	features []*Feature
+}
+
+type indexEntry struct {
+	bbox BoundingBox
+	f    *Feature
+}
+
+// BuildIndex constructs an in-memory R-tree over fc's features, bulk
+// loaded with the STR algorithm: features are sorted by bbox x-center
+// and split into about sqrt(N/indexFanout) vertical slabs (see
+// strSlabSize), each slab is sorted by
+// bbox y-center and packed into leaves of indexFanout features, and
+// those leaves are packed upward the same way until a single root
+// remains. Feature.Bbox is used when present, and computed from the
+// feature's geometry otherwise; features whose geometry can't be
+// parsed, or that have no coordinates at all, are left out of the
+// index. The returned Index is cached on fc and cleared by
+// AddFeatures, so mutating the collection requires calling BuildIndex
+// again before searching.
+func (t *FeatureCollection) BuildIndex() *Index {
+	entries := make([]indexEntry, 0, len(t.Features))
+	for _, f := range t.Features {
+		bbox, err := featureBBox(f)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, indexEntry{bbox, f})
+	}
+
+	idx := &Index{}
+	if len(entries) > 0 {
+		level := packLeaves(entries)
+		for len(level) > 1 {
+			level = packNodes(level)
+		}
+		idx.root = level[0]
+	}
+	t.index = idx
+	return idx
+}
+
+// packLeaves groups entries into leaf nodes of up to indexFanout
+// features each, via STR bulk loading.
+func packLeaves(entries []indexEntry) []*indexNode {
+	sort.Slice(entries, func(i, j int) bool {
+		return centerX(entries[i].bbox) < centerX(entries[j].bbox)
+	})
+	slabSize := strSlabSize(len(entries))
+
+	var leaves []*indexNode
+	for i := 0; i < len(entries); i += slabSize {
+		end := i + slabSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		slab := entries[i:end]
+		sort.Slice(slab, func(a, b int) bool {
+			return centerY(slab[a].bbox) < centerY(slab[b].bbox)
+		})
+		for j := 0; j < len(slab); j += indexFanout {
+			k := j + indexFanout
+			if k > len(slab) {
+				k = len(slab)
+			}
+			group := slab[j:k]
+			feats := make([]*Feature, len(group))
+			box := group[0].bbox
+			for gi, e := range group {
+				feats[gi] = e.f
+				box = unionBBox(box, e.bbox)
+			}
+			leaves = append(leaves, &indexNode{bbox: box, features: feats})
+		}
+	}
+	return leaves
+}
+
+// packNodes groups nodes into parent nodes of up to indexFanout
+// children each, repeating the same STR procedure as packLeaves one
+// level up.
+func packNodes(nodes []*indexNode) []*indexNode {
+	sort.Slice(nodes, func(i, j int) bool {
+		return centerX(nodes[i].bbox) < centerX(nodes[j].bbox)
+	})
+	slabSize := strSlabSize(len(nodes))
+
+	var packed []*indexNode
+	for i := 0; i < len(nodes); i += slabSize {
+		end := i + slabSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		slab := nodes[i:end]
+		sort.Slice(slab, func(a, b int) bool {
+			return centerY(slab[a].bbox) < centerY(slab[b].bbox)
+		})
+		for j := 0; j < len(slab); j += indexFanout {
+			k := j + indexFanout
+			if k > len(slab) {
+				k = len(slab)
+			}
+			group := append([]*indexNode(nil), slab[j:k]...)
+			box := group[0].bbox
+			for _, n := range group[1:] {
+				box = unionBBox(box, n.bbox)
+			}
+			packed = append(packed, &indexNode{bbox: box, children: group})
+		}
+	}
+	return packed
+}
+
+// strSlabSize returns how many (x-sorted) entries belong in each
+// vertical slab so that, once each slab is sorted by y and chunked into
+// indexFanout-sized pages, the page grid comes out close to square.
+func strSlabSize(n int) int {
+	pages := (n + indexFanout - 1) / indexFanout
+	slabs := int(math.Ceil(math.Sqrt(float64(pages))))
+	if slabs < 1 {
+		slabs = 1
+	}
+	return (n + slabs - 1) / slabs
+}
+
+func centerX(b BoundingBox) float64 { return (b[0] + b[2]) / 2 }
+func centerY(b BoundingBox) float64 { return (b[1] + b[3]) / 2 }
+
+func unionBBox(a, b BoundingBox) BoundingBox {
+	u := BoundingBox{a[0], a[1], a[2], a[3]}
+	if b[0] < u[0] {
+		u[0] = b[0]
+	}
+	if b[1] < u[1] {
+		u[1] = b[1]
+	}
+	if b[2] > u[2] {
+		u[2] = b[2]
+	}
+	if b[3] > u[3] {
+		u[3] = b[3]
+	}
+	return u
+}
+
+func bboxIntersects(a, b BoundingBox) bool {
+	return a[0] <= b[2] && b[0] <= a[2] && a[1] <= b[3] && b[1] <= a[3]
+}
+
+// featureBBox returns f's bounding box, using f.Bbox if it is already
+// populated and computing it from the geometry otherwise.
+func featureBBox(f *Feature) (BoundingBox, error) {
+	if len(f.Bbox) == 4 {
+		return f.Bbox, nil
+	}
+	g, err := f.GetGeometry()
+	if err != nil {
+		return nil, err
+	}
+	return geometryBBox(g)
+}
+
+func geometryBBox(g Geometry) (BoundingBox, error) {
+	var box BoundingBox
+	walkCoordinates(g, func(c Coordinate) {
+		if box == nil {
+			box = BoundingBox{c.X, c.Y, c.X, c.Y}
+			return
+		}
+		box = unionBBox(box, BoundingBox{c.X, c.Y, c.X, c.Y})
+	})
+	if box == nil {
+		return nil, fmt.Errorf("geojson: cannot compute bbox of empty geometry")
+	}
+	return box, nil
+}
+
+// walkCoordinates calls fn for every coordinate in g, recursing into
+// GeometryCollection members. It mirrors the per-type switch in
+// transformGeometry (reproject.go), but reads coordinates instead of
+// replacing them in place.
+func walkCoordinates(g Geometry, fn func(Coordinate)) {
+	switch t := g.(type) {
+	case *Point:
+		fn(t.Coordinates)
+	case *LineString:
+		for _, c := range t.Coordinates {
+			fn(c)
+		}
+	case *MultiPoint:
+		for _, c := range t.Coordinates {
+			fn(c)
+		}
+	case *MultiLineString:
+		for _, cc := range t.Coordinates {
+			for _, c := range cc {
+				fn(c)
+			}
+		}
+	case *Polygon:
+		for _, cc := range t.Coordinates {
+			for _, c := range cc {
+				fn(c)
+			}
+		}
+	case *MultiPolygon:
+		for _, pl := range t.Coordinates {
+			for _, cc := range pl {
+				for _, c := range cc {
+					fn(c)
+				}
+			}
+		}
+	case *GeometryCollection:
+		for _, sub := range t.Geometries {
+			walkCoordinates(sub, fn)
+		}
+	}
+}
+
+// SearchBBox returns every indexed feature whose bounding box
+// intersects box. With idx.Refine set, a candidate must additionally
+// have a vertex inside box, or (for a Polygon/MultiPolygon) contain one
+// of box's corners; this catches most false positives from the bbox
+// check but, unlike a true segment/segment intersection test, can miss
+// a box that cuts through a polygon edge without enclosing a vertex or
+// corner.
+func (idx *Index) SearchBBox(box BoundingBox) []*Feature {
+	if idx == nil || idx.root == nil {
+		return nil
+	}
+	var out []*Feature
+	searchBBox(idx.root, box, idx.Refine, &out)
+	return out
+}
+
+func searchBBox(n *indexNode, box BoundingBox, refine bool, out *[]*Feature) {
+	if !bboxIntersects(n.bbox, box) {
+		return
+	}
+	if n.features != nil {
+		for _, f := range n.features {
+			if !refine || featureIntersectsBBox(f, box) {
+				*out = append(*out, f)
+			}
+		}
+		return
+	}
+	for _, c := range n.children {
+		searchBBox(c, box, refine, out)
+	}
+}
+
+// SearchPoint returns every indexed feature whose bounding box contains
+// c. With idx.Refine set, a Polygon/MultiPolygon candidate is further
+// required to actually contain c under a ray-casting test; other
+// geometry types have no precise "contains a point" test and are kept
+// as bbox-only candidates.
+func (idx *Index) SearchPoint(c Coordinate) []*Feature {
+	if idx == nil || idx.root == nil {
+		return nil
+	}
+	box := BoundingBox{c.X, c.Y, c.X, c.Y}
+	var out []*Feature
+	searchPoint(idx.root, c, box, idx.Refine, &out)
+	return out
+}
+
+func searchPoint(n *indexNode, c Coordinate, box BoundingBox, refine bool, out *[]*Feature) {
+	if !bboxIntersects(n.bbox, box) {
+		return
+	}
+	if n.features != nil {
+		for _, f := range n.features {
+			if !refine || featureContainsPoint(f, c) {
+				*out = append(*out, f)
+			}
+		}
+		return
+	}
+	for _, ch := range n.children {
+		searchPoint(ch, c, box, refine, out)
+	}
+}
+
+// featureContainsPoint and featureIntersectsBBox reimplement the
+// ray-casting test geom.PointInPolygon already provides. They can't
+// call it directly: package geom imports geojson for the geometry
+// types, so geojson importing geom back would be a cycle.
+
+func featureContainsPoint(f *Feature, c Coordinate) bool {
+	g, err := f.GetGeometry()
+	if err != nil {
+		return false
+	}
+	switch t := g.(type) {
+	case *Polygon:
+		return pointInRings(c, t.Coordinates)
+	case *MultiPolygon:
+		for _, rings := range t.Coordinates {
+			if pointInRings(c, rings) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func featureIntersectsBBox(f *Feature, box BoundingBox) bool {
+	g, err := f.GetGeometry()
+	if err != nil {
+		return false
+	}
+	hit := false
+	walkCoordinates(g, func(c Coordinate) {
+		if !hit && c.X >= box[0] && c.X <= box[2] && c.Y >= box[1] && c.Y <= box[3] {
+			hit = true
+		}
+	})
+	if hit {
+		return true
+	}
+	corners := []Coordinate{
+		{X: box[0], Y: box[1]}, {X: box[2], Y: box[1]},
+		{X: box[2], Y: box[3]}, {X: box[0], Y: box[3]},
+	}
+	switch t := g.(type) {
+	case *Polygon:
+		for _, corner := range corners {
+			if pointInRings(corner, t.Coordinates) {
+				return true
+			}
+		}
+	case *MultiPolygon:
+		for _, rings := range t.Coordinates {
+			for _, corner := range corners {
+				if pointInRings(corner, rings) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func pointInRings(c Coordinate, rings MultiLine) bool {
+	if len(rings) == 0 || !rayCastRing(c, rings[0]) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if rayCastRing(c, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+func rayCastRing(c Coordinate, ring Coordinates) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pi, pj := ring[i], ring[j]
+		if (pi.Y > c.Y) != (pj.Y > c.Y) &&
+			c.X < (pj.X-pi.X)*(c.Y-pi.Y)/(pj.Y-pi.Y)+pi.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,27 @@
+package geojson
+
+import "testing"
+
+func TestIndexQueryConcreteGeometry(t *testing.T) {
+	fc := NewFeatureCollection([]*Feature{
+		NewFeature(NewPolygon(MultiLine{{
+			{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0},
+		}}), nil, "poly-a"),
+		NewFeature(NewPolygon(MultiLine{{
+			{X: 40, Y: 40}, {X: 60, Y: 40}, {X: 60, Y: 60}, {X: 40, Y: 60}, {X: 40, Y: 40},
+		}}), nil, "poly-b"),
+	})
+
+	idx := fc.BuildIndex()
+	idx.Refine = true
+
+	got := idx.SearchBBox(BoundingBox{0, 0, 2, 2})
+	if len(got) != 1 || got[0].Id != "poly-a" {
+		t.Fatalf("SearchBBox = %+v, want just poly-a", got)
+	}
+
+	got = idx.SearchPoint(Coordinate{X: 50, Y: 50})
+	if len(got) != 1 || got[0].Id != "poly-b" {
+		t.Fatalf("SearchPoint = %+v, want just poly-b", got)
+	}
+}
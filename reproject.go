@@ -0,0 +1,229 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadius is the WGS84 spherical radius (meters) used by the
+// Web Mercator forward/inverse formulas below.
+const earthRadius = 6378137.0
+
+type transformKey struct {
+	src, dst string
+}
+
+var transforms = map[transformKey]func(Coordinate) Coordinate{
+	{"EPSG:4326", "EPSG:3857"}: lonLatToWebMercator,
+	{"EPSG:3857", "EPSG:4326"}: webMercatorToLonLat,
+}
+
+// RegisterTransform installs fn as the coordinate transform Reproject
+// uses to go from src to dst, where src and dst are CRS names as they
+// appear in a "crs" member's urn:ogc:def:crs:... or EPSG:nnnn form.
+// Use this to plug in a proj4-backed transform for pairs this package
+// doesn't implement natively.
+func RegisterTransform(src, dst string, fn func(Coordinate) Coordinate) {
+	transforms[transformKey{normalizeCRS(src), normalizeCRS(dst)}] = fn
+}
+
+// normalizeCRS reduces the handful of CRS name spellings this package
+// recognizes to a canonical "EPSG:nnnn" form.
+func normalizeCRS(name string) string {
+	switch name {
+	case "urn:ogc:def:crs:EPSG::3857", "EPSG:3857":
+		return "EPSG:3857"
+	case "urn:ogc:def:crs:EPSG::4326", "EPSG:4326":
+		return "EPSG:4326"
+	case "urn:ogc:def:crs:OGC:1.3:CRS84", "urn:ogc:def:crs:OGC::CRS84", "CRS84":
+		return "EPSG:4326"
+	default:
+		return name
+	}
+}
+
+func lonLatToWebMercator(c Coordinate) Coordinate {
+	c.X = c.X * earthRadius * math.Pi / 180
+	c.Y = math.Log(math.Tan((90+c.Y)*math.Pi/360)) * earthRadius
+	return c
+}
+
+func webMercatorToLonLat(c Coordinate) Coordinate {
+	c.X = c.X / earthRadius * 180 / math.Pi
+	c.Y = (2*math.Atan(math.Exp(c.Y/earthRadius)) - math.Pi/2) * 180 / math.Pi
+	return c
+}
+
+// crsName returns the normalized CRS name attached to crs, defaulting
+// to WGS84 (RFC7946's mandated default) when crs is nil.
+func crsName(crs *CRS) string {
+	if crs == nil {
+		return "EPSG:4326"
+	}
+	return normalizeCRS(crs.Properties["name"])
+}
+
+func lookupTransform(src, dst string) (func(Coordinate) Coordinate, error) {
+	src, dst = normalizeCRS(src), normalizeCRS(dst)
+	if src == dst {
+		return func(c Coordinate) Coordinate { return c }, nil
+	}
+	fn, ok := transforms[transformKey{src, dst}]
+	if !ok {
+		return nil, fmt.Errorf("geojson: no transform registered from %s to %s", src, dst)
+	}
+	return fn, nil
+}
+
+func transformCoordinates(cc Coordinates, fn func(Coordinate) Coordinate) {
+	for i, c := range cc {
+		cc[i] = fn(c)
+	}
+}
+
+func transformMultiLine(ml MultiLine, fn func(Coordinate) Coordinate) {
+	for _, cc := range ml {
+		transformCoordinates(cc, fn)
+	}
+}
+
+func transformGeometry(g Geometry, fn func(Coordinate) Coordinate) {
+	switch t := g.(type) {
+	case *Point:
+		t.Coordinates = fn(t.Coordinates)
+	case *LineString:
+		transformCoordinates(t.Coordinates, fn)
+	case *MultiPoint:
+		transformCoordinates(t.Coordinates, fn)
+	case *MultiLineString:
+		transformMultiLine(t.Coordinates, fn)
+	case *Polygon:
+		transformMultiLine(t.Coordinates, fn)
+	case *MultiPolygon:
+		for _, pl := range t.Coordinates {
+			transformMultiLine(pl, fn)
+		}
+	case *GeometryCollection:
+		for _, geom := range t.Geometries {
+			transformGeometry(geom, fn)
+		}
+	}
+}
+
+// Reproject transforms p in place using the transform registered from
+// src to dst.
+func (p *Point) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	p.Coordinates = fn(p.Coordinates)
+	return nil
+}
+
+// Reproject transforms l in place using the transform registered from
+// src to dst.
+func (l *LineString) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	transformCoordinates(l.Coordinates, fn)
+	return nil
+}
+
+// Reproject transforms m in place using the transform registered from
+// src to dst.
+func (m *MultiPoint) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	transformCoordinates(m.Coordinates, fn)
+	return nil
+}
+
+// Reproject transforms m in place using the transform registered from
+// src to dst.
+func (m *MultiLineString) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	transformMultiLine(m.Coordinates, fn)
+	return nil
+}
+
+// Reproject transforms p in place using the transform registered from
+// src to dst.
+func (p *Polygon) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	transformMultiLine(p.Coordinates, fn)
+	return nil
+}
+
+// Reproject transforms m in place using the transform registered from
+// src to dst.
+func (m *MultiPolygon) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	for _, pl := range m.Coordinates {
+		transformMultiLine(pl, fn)
+	}
+	return nil
+}
+
+// Reproject transforms g and every nested geometry in place using the
+// transform registered from src to dst.
+func (g *GeometryCollection) Reproject(src, dst string) error {
+	fn, err := lookupTransform(src, dst)
+	if err != nil {
+		return err
+	}
+	for _, geom := range g.Geometries {
+		transformGeometry(geom, fn)
+	}
+	return nil
+}
+
+// Reproject transforms every feature's geometry in place from fc's
+// current CRS (defaulting to WGS84 if unset) to dst, a CRS name such as
+// "urn:ogc:def:crs:EPSG::3857", and updates Crs and Bbox to match: any
+// Bbox already set, on fc or on a feature, is recomputed from the
+// transformed coordinates.
+func (fc *FeatureCollection) Reproject(dst string) error {
+	fn, err := lookupTransform(crsName(fc.Crs), dst)
+	if err != nil {
+		return err
+	}
+	var all BoundingBox
+	for _, f := range fc.Features {
+		g, err := f.GetGeometry()
+		if err != nil {
+			return err
+		}
+		transformGeometry(g, fn)
+		f.Geometry = g
+		box, _ := geometryBBox(g)
+		if f.Bbox != nil {
+			f.Bbox = box
+		}
+		if box != nil {
+			if all == nil {
+				all = box
+			} else {
+				all = unionBBox(all, box)
+			}
+		}
+	}
+	fc.Crs = NewNamedCRS(dst)
+	if fc.Bbox != nil {
+		fc.Bbox = all
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package geojson
+
+import "testing"
+
+func TestFeatureCollectionReprojectConcreteGeometry(t *testing.T) {
+	fc := NewFeatureCollection([]*Feature{
+		NewFeature(NewPoint(Coordinate{X: 5, Y: 52}), nil, nil),
+	})
+
+	if err := fc.Reproject("EPSG:3857"); err != nil {
+		t.Fatalf("Reproject: %v", err)
+	}
+
+	p, ok := fc.Features[0].Geometry.(*Point)
+	if !ok {
+		t.Fatalf("Geometry = %T, want *Point", fc.Features[0].Geometry)
+	}
+	if p.Coordinates.X == 5 || p.Coordinates.Y == 52 {
+		t.Fatalf("coordinates were not transformed: %+v", p.Coordinates)
+	}
+	if fc.Crs == nil || fc.Crs.Properties["name"] != "EPSG:3857" {
+		t.Fatalf("Crs not updated to EPSG:3857: %+v", fc.Crs)
+	}
+}
@@ -0,0 +1,141 @@
+package topojson
+
+import "fmt"
+
+// arcBuilder cuts the quantized lines and polygon rings of a
+// FeatureCollection into the shared arcs a Topology stores, reusing an
+// arc (forward or reversed) wherever the same sequence of points
+// already exists.
+type arcBuilder struct {
+	shared map[[2][2]int64]bool
+	arcs   [][][2]int64
+	index  map[string]int
+}
+
+// newArcBuilder precomputes, over every line the collection contains,
+// which quantized segments are shared by more than one line - the
+// points where FromGeoJSON must cut an arc.
+func newArcBuilder(lines [][][2]int64) *arcBuilder {
+	counts := make(map[[2][2]int64]int)
+	for _, ln := range lines {
+		seen := make(map[[2][2]int64]bool)
+		for i := 0; i+1 < len(ln); i++ {
+			k := segmentKey(ln[i], ln[i+1])
+			if !seen[k] {
+				counts[k]++
+				seen[k] = true
+			}
+		}
+	}
+	shared := make(map[[2][2]int64]bool, len(counts))
+	for k, c := range counts {
+		if c >= 2 {
+			shared[k] = true
+		}
+	}
+	return &arcBuilder{shared: shared, index: make(map[string]int)}
+}
+
+// segmentKey normalizes a pair of adjacent quantized points so that the
+// same segment hashes equally regardless of which line traverses it in
+// which direction.
+func segmentKey(a, b [2]int64) [2][2]int64 {
+	if a[0] > b[0] || (a[0] == b[0] && a[1] > b[1]) {
+		a, b = b, a
+	}
+	return [2][2]int64{a, b}
+}
+
+// Line cuts points - a single quantized LineString or polygon ring -
+// into one or more arcs, starting a new arc wherever the line stops or
+// starts sharing a segment with another line, and returns the arc
+// references (TopoJSON's signed indices) that reconstruct the line when
+// resolved and concatenated in order. It errors rather than returning a
+// degenerate result if points collapses to fewer than two distinct
+// points once consecutive duplicates (an artifact of quantization
+// snapping nearby points together) are removed.
+func (b *arcBuilder) Line(points [][2]int64) ([]int, error) {
+	points = dedupConsecutive(points)
+	if len(points) < 2 {
+		return nil, fmt.Errorf("topojson: line has only %d distinct point(s) after quantization; increase Q", len(points))
+	}
+	var refs []int
+	start := 0
+	for i := 1; i < len(points)-1; i++ {
+		prevShared := b.shared[segmentKey(points[i-1], points[i])]
+		nextShared := b.shared[segmentKey(points[i], points[i+1])]
+		if prevShared != nextShared {
+			refs = append(refs, b.addArc(points[start:i+1]))
+			start = i
+		}
+	}
+	return append(refs, b.addArc(points[start:])), nil
+}
+
+// addArc returns the index of piece in b.arcs, adding it if no existing
+// arc already matches it forward or reversed. A match against an
+// existing arc's reverse is returned as its bitwise complement, the
+// TopoJSON convention for "traverse this arc back to front".
+func (b *arcBuilder) addArc(piece [][2]int64) int {
+	cp := append([][2]int64(nil), piece...)
+	fwd := arcKey(cp)
+	if idx, ok := b.index[fwd]; ok {
+		return idx
+	}
+	rev := arcKey(reversePoints(cp))
+	if idx, ok := b.index[rev]; ok {
+		return ^idx
+	}
+	idx := len(b.arcs)
+	b.arcs = append(b.arcs, cp)
+	b.index[fwd] = idx
+	return idx
+}
+
+func arcKey(points [][2]int64) string {
+	buf := make([]byte, 0, len(points)*12)
+	for _, p := range points {
+		buf = appendInt(buf, p[0])
+		buf = append(buf, ',')
+		buf = appendInt(buf, p[1])
+		buf = append(buf, ';')
+	}
+	return string(buf)
+}
+
+func appendInt(buf []byte, v int64) []byte {
+	if v < 0 {
+		buf = append(buf, '-')
+		v = -v
+	}
+	start := len(buf)
+	if v == 0 {
+		return append(buf, '0')
+	}
+	for v > 0 {
+		buf = append(buf, byte('0'+v%10))
+		v /= 10
+	}
+	for i, j := start, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return buf
+}
+
+func reversePoints(points [][2]int64) [][2]int64 {
+	out := make([][2]int64, len(points))
+	for i, p := range points {
+		out[len(points)-1-i] = p
+	}
+	return out
+}
+
+func dedupConsecutive(points [][2]int64) [][2]int64 {
+	out := make([][2]int64, 0, len(points))
+	for i, p := range points {
+		if i == 0 || p != points[i-1] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,223 @@
+package topojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sputnik-maps/geojson"
+)
+
+// ToGeoJSON reverses FromGeoJSON: it resolves every object's arc
+// references back into coordinates and returns one Feature per
+// geometry found in t.Objects. A "GeometryCollection" object (what
+// FromGeoJSON always produces) contributes one Feature per member
+// geometry, using that geometry's own Id and Properties; any other
+// object contributes a single Feature. Multiple objects are visited in
+// sorted key order, for a deterministic result.
+func ToGeoJSON(t *Topology) (*geojson.FeatureCollection, error) {
+	if t == nil {
+		return nil, errors.New("topojson: nil Topology")
+	}
+	arcs, err := resolveArcs(t.Arcs)
+	if err != nil {
+		return nil, err
+	}
+	tr := t.Transform
+	if tr == nil {
+		tr = &Transform{Scale: [2]float64{1, 1}}
+	}
+
+	keys := make([]string, 0, len(t.Objects))
+	for k := range t.Objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var features []*geojson.Feature
+	for _, k := range keys {
+		fs, err := objectFeatures(t.Objects[k], arcs, tr)
+		if err != nil {
+			return nil, fmt.Errorf("topojson: object %q: %w", k, err)
+		}
+		features = append(features, fs...)
+	}
+	return geojson.NewFeatureCollection(features), nil
+}
+
+func objectFeatures(obj *Geometry, arcs [][][2]int64, tr *Transform) ([]*geojson.Feature, error) {
+	if obj.Type == "GeometryCollection" {
+		features := make([]*geojson.Feature, 0, len(obj.Geometries))
+		for _, sub := range obj.Geometries {
+			g, err := geometryFromTopo(sub, arcs, tr)
+			if err != nil {
+				return nil, err
+			}
+			features = append(features, geojson.NewFeature(g, sub.Properties, sub.Id))
+		}
+		return features, nil
+	}
+	g, err := geometryFromTopo(obj, arcs, tr)
+	if err != nil {
+		return nil, err
+	}
+	return []*geojson.Feature{geojson.NewFeature(g, obj.Properties, obj.Id)}, nil
+}
+
+func geometryFromTopo(tg *Geometry, arcs [][][2]int64, tr *Transform) (geojson.Geometry, error) {
+	switch tg.Type {
+	case "Point":
+		var p [2]int64
+		if err := json.Unmarshal(tg.Coordinates, &p); err != nil {
+			return nil, fmt.Errorf("Point coordinates: %w", err)
+		}
+		return geojson.NewPoint(dequantizePoint(p, tr)), nil
+	case "MultiPoint":
+		var pts [][2]int64
+		if err := json.Unmarshal(tg.Coordinates, &pts); err != nil {
+			return nil, fmt.Errorf("MultiPoint coordinates: %w", err)
+		}
+		return geojson.NewMultiPoint(dequantizeRing(pts, tr)), nil
+	case "LineString":
+		var refs []int
+		if err := json.Unmarshal(tg.Arcs, &refs); err != nil {
+			return nil, fmt.Errorf("LineString arcs: %w", err)
+		}
+		pts, err := resolveLine(refs, arcs)
+		if err != nil {
+			return nil, err
+		}
+		return geojson.NewLineString(dequantizeRing(pts, tr)), nil
+	case "MultiLineString":
+		var refs [][]int
+		if err := json.Unmarshal(tg.Arcs, &refs); err != nil {
+			return nil, fmt.Errorf("MultiLineString arcs: %w", err)
+		}
+		ml := make(geojson.MultiLine, len(refs))
+		for i, line := range refs {
+			pts, err := resolveLine(line, arcs)
+			if err != nil {
+				return nil, err
+			}
+			ml[i] = dequantizeRing(pts, tr)
+		}
+		return geojson.NewMultiLineString(ml), nil
+	case "Polygon":
+		var refs [][]int
+		if err := json.Unmarshal(tg.Arcs, &refs); err != nil {
+			return nil, fmt.Errorf("Polygon arcs: %w", err)
+		}
+		pl, err := resolveRings(refs, arcs, tr)
+		if err != nil {
+			return nil, err
+		}
+		return geojson.NewPolygon(pl), nil
+	case "MultiPolygon":
+		var refs [][][]int
+		if err := json.Unmarshal(tg.Arcs, &refs); err != nil {
+			return nil, fmt.Errorf("MultiPolygon arcs: %w", err)
+		}
+		mp := make([]geojson.MultiLine, len(refs))
+		for i, poly := range refs {
+			pl, err := resolveRings(poly, arcs, tr)
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = pl
+		}
+		return geojson.NewMultiPolygon(mp), nil
+	case "GeometryCollection":
+		gc := geojson.NewGeometryCollection()
+		for _, sub := range tg.Geometries {
+			g, err := geometryFromTopo(sub, arcs, tr)
+			if err != nil {
+				return nil, err
+			}
+			gc.Geometries = append(gc.Geometries, g)
+		}
+		return gc, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", tg.Type)
+	}
+}
+
+func resolveRings(refs [][]int, arcs [][][2]int64, tr *Transform) (geojson.MultiLine, error) {
+	pl := make(geojson.MultiLine, len(refs))
+	for i, ring := range refs {
+		pts, err := resolveLine(ring, arcs)
+		if err != nil {
+			return nil, err
+		}
+		pl[i] = dequantizeRing(pts, tr)
+	}
+	return pl, nil
+}
+
+// resolveArcs turns a Topology's delta-encoded Arcs back into absolute
+// quantized points.
+func resolveArcs(deltas [][][2]int64) ([][][2]int64, error) {
+	out := make([][][2]int64, len(deltas))
+	for i, arc := range deltas {
+		if len(arc) == 0 {
+			continue
+		}
+		abs := make([][2]int64, len(arc))
+		abs[0] = arc[0]
+		for j := 1; j < len(arc); j++ {
+			abs[j] = [2]int64{abs[j-1][0] + arc[j][0], abs[j-1][1] + arc[j][1]}
+		}
+		out[i] = abs
+	}
+	return out, nil
+}
+
+// resolveLine resolves a sequence of arc references into the points of
+// the line or ring they describe, dropping the point each arc shares
+// with the next so it isn't duplicated.
+func resolveLine(refs []int, arcs [][][2]int64) ([][2]int64, error) {
+	var out [][2]int64
+	for i, ref := range refs {
+		pts, err := resolveArcRef(ref, arcs)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && len(pts) > 0 {
+			pts = pts[1:]
+		}
+		out = append(out, pts...)
+	}
+	return out, nil
+}
+
+func resolveArcRef(ref int, arcs [][][2]int64) ([][2]int64, error) {
+	idx := ref
+	reversed := false
+	if ref < 0 {
+		idx = ^ref
+		reversed = true
+	}
+	if idx < 0 || idx >= len(arcs) {
+		return nil, fmt.Errorf("arc index %d out of range", ref)
+	}
+	pts := arcs[idx]
+	if reversed {
+		pts = reversePoints(pts)
+	}
+	return pts, nil
+}
+
+func dequantizePoint(p [2]int64, tr *Transform) geojson.Coordinate {
+	return geojson.Coordinate{
+		X: tr.Translate[0] + float64(p[0])*tr.Scale[0],
+		Y: tr.Translate[1] + float64(p[1])*tr.Scale[1],
+	}
+}
+
+func dequantizeRing(points [][2]int64, tr *Transform) geojson.Coordinates {
+	cc := make(geojson.Coordinates, len(points))
+	for i, p := range points {
+		cc[i] = dequantizePoint(p, tr)
+	}
+	return cc
+}
@@ -0,0 +1,305 @@
+package topojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/sputnik-maps/geojson"
+)
+
+// DefaultQuantization is the Q FromGeoJSON uses: coordinates are
+// snapped to a Q x Q integer grid, derived from the collection's bbox,
+// before arcs are cut.
+const DefaultQuantization = 1e4
+
+// FromGeoJSON converts fc to a Topology, quantizing coordinates with
+// DefaultQuantization. Use FromGeoJSONQuantized to choose a different
+// Q - a larger grid preserves more precision at the cost of larger
+// delta-encoded integers.
+func FromGeoJSON(fc *geojson.FeatureCollection) (*Topology, error) {
+	return FromGeoJSONQuantized(fc, DefaultQuantization)
+}
+
+// FromGeoJSONQuantized builds a Topology the same way FromGeoJSON does,
+// using q in place of DefaultQuantization. Every feature becomes one
+// geometry in a single "collection" object; every LineString and
+// Polygon ring is cut into arcs wherever it starts or stops sharing a
+// quantized segment with another line or ring in fc, so a boundary
+// shared between adjoining features (administrative areas, parcels,
+// tiles) is stored once and referenced from both. Only X/Y are encoded
+// - a Coordinate with Z or M set is rejected, since TopoJSON arcs have
+// no way to carry a third/fourth value per shared point.
+func FromGeoJSONQuantized(fc *geojson.FeatureCollection, q float64) (*Topology, error) {
+	geoms := make([]geojson.Geometry, len(fc.Features))
+	for i, f := range fc.Features {
+		g, err := f.GetGeometry()
+		if err != nil {
+			return nil, fmt.Errorf("topojson: feature %d: %w", i, err)
+		}
+		geoms[i] = g
+	}
+
+	minX, minY, maxX, maxY, err := bboxOf(geoms)
+	if err != nil {
+		return nil, err
+	}
+	tr := computeTransform(minX, minY, maxX, maxY, q)
+
+	var allLines [][][2]int64
+	for _, g := range geoms {
+		collectLines(g, tr, &allLines)
+	}
+
+	b := newArcBuilder(allLines)
+	out := make([]*Geometry, len(fc.Features))
+	for i, f := range fc.Features {
+		tg, err := buildGeometry(f, geoms[i], tr, b)
+		if err != nil {
+			return nil, fmt.Errorf("topojson: feature %d: %w", i, err)
+		}
+		out[i] = tg
+	}
+
+	return &Topology{
+		Type:      "Topology",
+		Transform: tr,
+		Objects:   map[string]*Geometry{"collection": {Type: "GeometryCollection", Geometries: out}},
+		Arcs:      deltaEncodeAll(b.arcs),
+	}, nil
+}
+
+// bboxOf returns the bounding box of every coordinate across geoms, the
+// basis for FromGeoJSONQuantized's transform. It also rejects any
+// coordinate carrying a Z or M value, which this package has no way to
+// represent in a TopoJSON arc.
+func bboxOf(geoms []geojson.Geometry) (minX, minY, maxX, maxY float64, err error) {
+	first := true
+	for _, g := range geoms {
+		walkCoords(g, func(c geojson.Coordinate) {
+			if err != nil {
+				return
+			}
+			if c.HasZ || c.HasM {
+				err = errors.New("topojson: Z/M coordinates are not supported; FromGeoJSON only encodes X/Y")
+				return
+			}
+			if first {
+				minX, maxX, minY, maxY = c.X, c.X, c.Y, c.Y
+				first = false
+				return
+			}
+			if c.X < minX {
+				minX = c.X
+			}
+			if c.X > maxX {
+				maxX = c.X
+			}
+			if c.Y < minY {
+				minY = c.Y
+			}
+			if c.Y > maxY {
+				maxY = c.Y
+			}
+		})
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	if first {
+		return 0, 0, 0, 0, errors.New("topojson: FeatureCollection has no coordinates to index")
+	}
+	return minX, minY, maxX, maxY, nil
+}
+
+func computeTransform(minX, minY, maxX, maxY, q float64) *Transform {
+	sx, sy := 1.0, 1.0
+	if q > 1 {
+		if maxX > minX {
+			sx = (maxX - minX) / (q - 1)
+		}
+		if maxY > minY {
+			sy = (maxY - minY) / (q - 1)
+		}
+	}
+	return &Transform{Scale: [2]float64{sx, sy}, Translate: [2]float64{minX, minY}}
+}
+
+func quantizePoint(c geojson.Coordinate, tr *Transform) [2]int64 {
+	return [2]int64{
+		int64(math.Round((c.X - tr.Translate[0]) / tr.Scale[0])),
+		int64(math.Round((c.Y - tr.Translate[1]) / tr.Scale[1])),
+	}
+}
+
+func quantizeRing(cc geojson.Coordinates, tr *Transform) [][2]int64 {
+	out := make([][2]int64, len(cc))
+	for i, c := range cc {
+		out[i] = quantizePoint(c, tr)
+	}
+	return out
+}
+
+// walkCoords calls fn for every coordinate in g, recursing into
+// GeometryCollection members.
+func walkCoords(g geojson.Geometry, fn func(geojson.Coordinate)) {
+	switch t := g.(type) {
+	case *geojson.Point:
+		fn(t.Coordinates)
+	case *geojson.LineString:
+		for _, c := range t.Coordinates {
+			fn(c)
+		}
+	case *geojson.MultiPoint:
+		for _, c := range t.Coordinates {
+			fn(c)
+		}
+	case *geojson.MultiLineString:
+		for _, cc := range t.Coordinates {
+			for _, c := range cc {
+				fn(c)
+			}
+		}
+	case *geojson.Polygon:
+		for _, cc := range t.Coordinates {
+			for _, c := range cc {
+				fn(c)
+			}
+		}
+	case *geojson.MultiPolygon:
+		for _, pl := range t.Coordinates {
+			for _, cc := range pl {
+				for _, c := range cc {
+					fn(c)
+				}
+			}
+		}
+	case *geojson.GeometryCollection:
+		for _, sub := range t.Geometries {
+			walkCoords(sub, fn)
+		}
+	}
+}
+
+// collectLines appends the quantized form of every LineString and
+// Polygon ring in g to *out. It visits geometry in the same order
+// buildGeometry does, but only to let newArcBuilder see every line in
+// the collection before any of them is cut into arcs.
+func collectLines(g geojson.Geometry, tr *Transform, out *[][][2]int64) {
+	switch t := g.(type) {
+	case *geojson.LineString:
+		*out = append(*out, quantizeRing(t.Coordinates, tr))
+	case *geojson.MultiLineString:
+		for _, cc := range t.Coordinates {
+			*out = append(*out, quantizeRing(cc, tr))
+		}
+	case *geojson.Polygon:
+		for _, ring := range t.Coordinates {
+			*out = append(*out, quantizeRing(ring, tr))
+		}
+	case *geojson.MultiPolygon:
+		for _, poly := range t.Coordinates {
+			for _, ring := range poly {
+				*out = append(*out, quantizeRing(ring, tr))
+			}
+		}
+	case *geojson.GeometryCollection:
+		for _, sub := range t.Geometries {
+			collectLines(sub, tr, out)
+		}
+	}
+}
+
+// buildGeometry converts f's geometry to a TopoJSON Geometry, attaching
+// f's Id and Properties to the result.
+func buildGeometry(f *geojson.Feature, g geojson.Geometry, tr *Transform, b *arcBuilder) (*Geometry, error) {
+	tg, err := buildGeometryType(g, tr, b)
+	if err != nil {
+		return nil, err
+	}
+	tg.Id = f.Id
+	tg.Properties = f.Properties
+	return tg, nil
+}
+
+func buildGeometryType(g geojson.Geometry, tr *Transform, b *arcBuilder) (*Geometry, error) {
+	switch t := g.(type) {
+	case *geojson.Point:
+		return &Geometry{Type: "Point", Coordinates: rawJSON(quantizePoint(t.Coordinates, tr))}, nil
+	case *geojson.MultiPoint:
+		return &Geometry{Type: "MultiPoint", Coordinates: rawJSON(quantizeRing(t.Coordinates, tr))}, nil
+	case *geojson.LineString:
+		refs, err := b.Line(quantizeRing(t.Coordinates, tr))
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "LineString", Arcs: rawJSON(refs)}, nil
+	case *geojson.MultiLineString:
+		refs := make([][]int, len(t.Coordinates))
+		for i, cc := range t.Coordinates {
+			r, err := b.Line(quantizeRing(cc, tr))
+			if err != nil {
+				return nil, err
+			}
+			refs[i] = r
+		}
+		return &Geometry{Type: "MultiLineString", Arcs: rawJSON(refs)}, nil
+	case *geojson.Polygon:
+		refs, err := polygonArcs(t.Coordinates, tr, b)
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "Polygon", Arcs: rawJSON(refs)}, nil
+	case *geojson.MultiPolygon:
+		refs := make([][][]int, len(t.Coordinates))
+		for i, poly := range t.Coordinates {
+			r, err := polygonArcs(poly, tr, b)
+			if err != nil {
+				return nil, err
+			}
+			refs[i] = r
+		}
+		return &Geometry{Type: "MultiPolygon", Arcs: rawJSON(refs)}, nil
+	case *geojson.GeometryCollection:
+		subs := make([]*Geometry, len(t.Geometries))
+		for i, sub := range t.Geometries {
+			sg, err := buildGeometryType(sub, tr, b)
+			if err != nil {
+				return nil, err
+			}
+			subs[i] = sg
+		}
+		return &Geometry{Type: "GeometryCollection", Geometries: subs}, nil
+	default:
+		return nil, fmt.Errorf("topojson: unsupported geometry type %T", g)
+	}
+}
+
+func polygonArcs(rings geojson.MultiLine, tr *Transform, b *arcBuilder) ([][]int, error) {
+	out := make([][]int, len(rings))
+	for i, ring := range rings {
+		refs, err := b.Line(quantizeRing(ring, tr))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = refs
+	}
+	return out, nil
+}
+
+func deltaEncodeAll(arcs [][][2]int64) [][][2]int64 {
+	out := make([][][2]int64, len(arcs))
+	for i, arc := range arcs {
+		out[i] = deltaEncode(arc)
+	}
+	return out
+}
+
+func deltaEncode(arc [][2]int64) [][2]int64 {
+	out := make([][2]int64, len(arc))
+	out[0] = arc[0]
+	for i := 1; i < len(arc); i++ {
+		out[i] = [2]int64{arc[i][0] - arc[i-1][0], arc[i][1] - arc[i-1][1]}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+/*
+Package topojson converts between FeatureCollections from package
+geojson and TopoJSON Topology objects. TopoJSON stores each boundary
+arc once and has every feature that shares it refer back by index,
+which typically shrinks a GeoJSON payload of adjoining polygons
+(administrative areas, parcels, tiles) 5-10x relative to repeating
+every ring's coordinates in full.
+
+See https://github.com/topojson/topojson-specification for the wire
+format this package reads and writes.
+*/
+package topojson
+
+import "encoding/json"
+
+// Transform is TopoJSON's scale/translate pair for recovering
+// real-world coordinates from the quantized integers stored in a
+// Topology's Arcs: x = translate[0] + scale[0]*qx, and likewise for y.
+type Transform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+// Topology is a TopoJSON Topology object. Arcs holds every arc in
+// delta-encoded form (each arc's first point is absolute, the rest are
+// offsets from the previous point) so that the small integers produced
+// by quantization stay small in the JSON encoding too. Objects maps a
+// name to the geometry (or, as FromGeoJSON always produces, a single
+// GeometryCollection of per-feature geometries) built over those arcs.
+type Topology struct {
+	Type      string               `json:"type"`
+	Transform *Transform           `json:"transform,omitempty"`
+	Objects   map[string]*Geometry `json:"objects"`
+	Arcs      [][][2]int64         `json:"arcs"`
+}
+
+// Geometry is a TopoJSON geometry object: like a GeoJSON geometry, but
+// a LineString/Polygon and their Multi forms reference arcs instead of
+// carrying coordinates, so Arcs is nested to the same depth Coordinates
+// would be for the equivalent GeoJSON type (a plain list for
+// LineString, a list of rings for Polygon, and so on), with each
+// element a signed index into the Topology's Arcs: a negative value ~i
+// means "arc i traversed back to front". Point and MultiPoint have no
+// path to share, so they keep quantized coordinates directly in
+// Coordinates instead.
+type Geometry struct {
+	Type        string                 `json:"type"`
+	Id          interface{}            `json:"id,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+	Arcs        json.RawMessage        `json:"arcs,omitempty"`
+	Geometries  []*Geometry            `json:"geometries,omitempty"`
+}
+
+// rawJSON marshals v, a value built from this package's own ints and
+// slices of ints, into a json.RawMessage. Such a value is always
+// representable as JSON, so the error is discarded.
+func rawJSON(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
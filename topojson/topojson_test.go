@@ -0,0 +1,45 @@
+package topojson
+
+import (
+	"testing"
+
+	"github.com/sputnik-maps/geojson"
+)
+
+func TestRoundTripConcreteGeometry(t *testing.T) {
+	fc := geojson.NewFeatureCollection([]*geojson.Feature{
+		geojson.NewFeature(geojson.NewPolygon(geojson.MultiLine{{
+			{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0},
+		}}), map[string]interface{}{"name": "a"}, "a"),
+		geojson.NewFeature(geojson.NewPolygon(geojson.MultiLine{{
+			{X: 10, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0},
+		}}), map[string]interface{}{"name": "b"}, "b"),
+	})
+
+	topo, err := FromGeoJSON(fc)
+	if err != nil {
+		t.Fatalf("FromGeoJSON: %v", err)
+	}
+	// The shared edge between the two squares should collapse to a
+	// single arc, referenced with opposite sign/direction by each: the
+	// first square is cut into 3 arcs and the second adds only 1 more.
+	if len(topo.Arcs) != 4 {
+		t.Fatalf("len(Arcs) = %d, want the shared edge deduplicated", len(topo.Arcs))
+	}
+
+	out, err := ToGeoJSON(topo)
+	if err != nil {
+		t.Fatalf("ToGeoJSON: %v", err)
+	}
+	if len(out.Features) != 2 {
+		t.Fatalf("len(Features) = %d, want 2", len(out.Features))
+	}
+
+	// ToGeoJSON hands back Features with a concrete Geometry; calling
+	// GetGeometry on the round-tripped result must not panic.
+	for _, f := range out.Features {
+		if _, err := f.GetGeometry(); err != nil {
+			t.Fatalf("GetGeometry on round-tripped feature: %v", err)
+		}
+	}
+}